@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/cmd/server/app/options"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/admin"
+)
+
+// componentName is how this process registers itself with the configz
+// registry, and what appears under "componentconfig" at /configz.
+const componentName = "proxy-server"
+
+// runAdminServer starts the admin HTTP listener (AdminBindAddress:AdminPort)
+// serving pprof, /configz, and /flagz, and reports any listen/serve error on
+// errCh. It returns once the listener is up so callers know /configz etc.
+// are reachable before moving on to the next listener.
+//
+// metricsRegistry is also registered on this mux at /metrics unless
+// o.MetricsPort is non-zero, in which case metrics get their own listener
+// via runMetricsServer instead.
+func runAdminServer(o *options.ProxyRunOptions, flags *pflag.FlagSet, metricsRegistry *prometheus.Registry, errCh chan<- error) error {
+	muxMetrics := metricsRegistry
+	if o.MetricsPort != 0 {
+		muxMetrics = nil
+	}
+	mux := admin.NewMux(admin.Config{
+		EnableProfiling:           o.EnableProfiling,
+		EnableContentionProfiling: o.EnableContentionProfiling,
+	}, componentName, o.Redacted(), flags, muxMetrics)
+
+	addr := net.JoinHostPort(o.AdminBindAddress, fmt.Sprintf("%d", o.AdminPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind admin listener %s: %v", addr, err)
+	}
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		klog.Infof("admin listener serving pprof/configz/flagz on %s", addr)
+		errCh <- server.Serve(ln)
+	}()
+
+	return nil
+}
+
+// runMetricsServer starts a dedicated metrics listener
+// (MetricsBindAddress:MetricsPort) serving /metrics from metricsRegistry.
+// Only called when o.MetricsPort is non-zero; otherwise metrics are served
+// on the admin listener by runAdminServer.
+func runMetricsServer(o *options.ProxyRunOptions, metricsRegistry *prometheus.Registry, errCh chan<- error) error {
+	if o.MetricsPort == 0 {
+		return nil
+	}
+
+	mux := admin.NewMetricsMux(metricsRegistry)
+	addr := net.JoinHostPort(o.MetricsBindAddress, fmt.Sprintf("%d", o.MetricsPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind metrics listener %s: %v", addr, err)
+	}
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		klog.Infof("metrics listener serving /metrics on %s", addr)
+		errCh <- server.Serve(ln)
+	}()
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/cmd/server/app/options"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/agentlistener"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/certreload"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+// runAgentListener starts the agent tunnel listener (AgentBindAddress:
+// AgentPort) under --dev-direct-dial-mode only (see that flag's docs), and
+// tracks accepted connections in m.Tunnels. The real gRPC tunnel protocol
+// is out of scope here (it lives in pkg/server, which this tree doesn't
+// contain); handleAgentConn is a dev-only placeholder that just holds the
+// connection open rather than speaking that protocol, so this must not run
+// by default — it would silently accept "agent" connections that are never
+// actually usable as tunnels. It returns the CertReloader backing the
+// listener's certificate (nil if no cert is configured) so the caller can
+// keep it fresh via runConfigReloadWatcher.
+func runAgentListener(o *options.ProxyRunOptions, m *metrics.ServerMetrics, strategies *certreload.StrategyReloader, errCh chan<- error) (*certreload.CertReloader, error) {
+	if !o.DevDirectDialMode {
+		return nil, nil
+	}
+
+	addr := net.JoinHostPort(o.AgentBindAddress, fmt.Sprintf("%d", o.AgentPort))
+
+	tlsConfig, reloader, err := maybeAgentTLSConfig(o, strategies)
+	if err != nil {
+		return nil, err
+	}
+
+	var ln net.Listener
+	if tlsConfig != nil {
+		ln, err = tls.Listen("tcp", addr, tlsConfig)
+	} else {
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind agent listener %s: %v", addr, err)
+	}
+
+	go func() {
+		klog.Infof("agent listener accepting tunnels on %s", addr)
+		errCh <- agentlistener.Serve(ln, m, handleAgentConn)
+	}()
+
+	return reloader, nil
+}
+
+func handleAgentConn(conn net.Conn) {
+	buf := make([]byte, 1)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+	}
+}
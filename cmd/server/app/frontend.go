@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/cmd/server/app/options"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/authz"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/certreload"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/frontend"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+// netDialer is the frontend.Dialer used in --dev-direct-dial-mode: it
+// dials destHost directly from this process instead of routing through an
+// agent tunnel (which this tree's code doesn't implement). Only correct
+// when the proxy server and the destination share a network, e.g. local
+// dev/e2e; never use it as a stand-in for real agent connectivity.
+type netDialer struct{}
+
+func (netDialer) DialContext(ctx context.Context, destHost string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", destHost)
+}
+
+// runFrontendServer starts the "http-connect" mode frontend listener
+// (ServerBindAddress:ServerPort), serving TLS from o.ServerCert/ServerKey
+// if set. It only runs under --dev-direct-dial-mode (see that flag's docs):
+// without a real agent tunnel implementation in this tree, this listener
+// can only dial destinations directly, which is not safe to expose as a
+// default code path. In "grpc" mode, dials are instead authorized and
+// metered at the equivalent point in the gRPC tunnel's backend-selection
+// code, so there is nothing for this function to start. It returns the
+// CertReloader backing the listener's certificate (nil if no cert is
+// configured) so the caller can keep it fresh via runConfigReloadWatcher.
+//
+// The handler is always built with authz.AlwaysAllowAuthorizer, never
+// o.Authorizer(): the CONNECT request this listener authorizes comes from
+// the Kube API Server, not from an agent, so it has no ServiceAccount/
+// NodeCIDR/Labels identity for an --agent-authorization-config rule to
+// match against. o.Validate rejects the combination of the two flags so
+// that mismatch can't be configured by accident.
+func runFrontendServer(o *options.ProxyRunOptions, m *metrics.ServerMetrics, strategies *certreload.StrategyReloader, errCh chan<- error) (*certreload.CertReloader, error) {
+	if !o.DevDirectDialMode {
+		return nil, nil
+	}
+	if o.Mode != "http-connect" {
+		return nil, nil
+	}
+	if o.UdsName != "" {
+		return nil, nil
+	}
+
+	handler := frontend.NewHandler(authz.AlwaysAllowAuthorizer, netDialer{}, m)
+	addr := net.JoinHostPort(o.ServerBindAddress, fmt.Sprintf("%d", o.ServerPort))
+
+	tlsConfig, reloader, err := maybeFrontendTLSConfig(o, strategies)
+	if err != nil {
+		return nil, err
+	}
+
+	var ln net.Listener
+	if tlsConfig != nil {
+		ln, err = tls.Listen("tcp", addr, tlsConfig)
+	} else {
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind frontend listener %s: %v", addr, err)
+	}
+
+	server := &http.Server{Handler: handler}
+	go func() {
+		klog.Infof("frontend listener serving HTTP CONNECT on %s", addr)
+		errCh <- server.Serve(ln)
+	}()
+
+	return reloader, nil
+}
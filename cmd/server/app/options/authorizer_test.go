@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/authz"
+)
+
+func TestAuthorizerDefaultsToAlwaysAllow(t *testing.T) {
+	o := NewProxyRunOptions()
+
+	authorizer, err := o.Authorizer()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authorizer != authz.AlwaysAllowAuthorizer {
+		t.Errorf("Authorizer() = %v, want authz.AlwaysAllowAuthorizer", authorizer)
+	}
+}
+
+func TestAuthorizerEnforcesConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-authz.yaml")
+	contents := `rules:
+- match:
+    nodeCIDR: 10.0.0.0/8
+  allow:
+    destHostSuffixes:
+    - .svc.cluster.local
+`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	o := NewProxyRunOptions()
+	o.AgentAuthorizationConfig = path
+
+	authorizer, err := o.Authorizer()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allowedAgent := authz.AgentIdentity{NodeAddr: net.ParseIP("10.1.2.3")}
+	if err := authorizer.AuthorizeDial(allowedAgent, "kubernetes.default.svc.cluster.local"); err != nil {
+		t.Errorf("AuthorizeDial() = %v, want nil for allowed agent/destination", err)
+	}
+	if err := authorizer.AuthorizeDial(allowedAgent, "example.com"); err == nil {
+		t.Error("AuthorizeDial() = nil, want error for disallowed destination")
+	}
+}
+
+func TestAuthorizerRejectsMalformedConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-authz.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n- match:\n    nodeCIDR: not-a-cidr\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	o := NewProxyRunOptions()
+	o.AgentAuthorizationConfig = path
+
+	if _, err := o.Authorizer(); err == nil {
+		t.Error("Authorizer() = nil error, want error for malformed nodeCIDR")
+	}
+}
+
+func TestValidateRejectsAgentAuthorizationConfigWithDevDirectDialMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-authz.yaml")
+	contents := `rules:
+- match:
+    nodeCIDR: 10.0.0.0/8
+  allow:
+    destHostSuffixes:
+    - .svc.cluster.local
+`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	o := NewProxyRunOptions()
+	o.AgentAuthorizationConfig = path
+	o.DevDirectDialMode = true
+
+	if err := o.Validate(); err == nil {
+		t.Error("Validate() = nil error, want error: --dev-direct-dial-mode's listener has no agent identity for --agent-authorization-config to match against")
+	}
+}
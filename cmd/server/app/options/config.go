@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	proxyserverinstall "sigs.k8s.io/apiserver-network-proxy/pkg/apis/proxyserver/install"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/apis/proxyserver/v1alpha1"
+)
+
+// ApplyConfigFromFile decodes the versioned ProxyServerConfiguration at
+// o.ConfigFile and merges its values into o. This mirrors how kube-scheduler
+// and kubelet merge a loaded componentconfig with command-line flags: values
+// from the file are applied first, and any flag explicitly set on the
+// command line (as reported by flags.Changed) wins.
+//
+// It is a no-op when o.ConfigFile is empty. Callers should invoke this after
+// flags.Parse() and before o.Validate().
+func (o *ProxyRunOptions) ApplyConfigFromFile(flags *pflag.FlagSet) error {
+	if o.ConfigFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(o.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to read --config %q: %v", o.ConfigFile, err)
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse --config %q as YAML or JSON: %v", o.ConfigFile, err)
+	}
+
+	scheme, codecs, err := proxyserverinstall.NewScheme()
+	if err != nil {
+		return fmt.Errorf("failed to build config scheme: %v", err)
+	}
+	obj, gvk, err := codecs.UniversalDecoder(v1alpha1.SchemeGroupVersion).Decode(jsonData, nil, &v1alpha1.ProxyServerConfiguration{})
+	if err != nil {
+		return fmt.Errorf("failed to decode --config %q: %v", o.ConfigFile, err)
+	}
+	cfg, ok := obj.(*v1alpha1.ProxyServerConfiguration)
+	if !ok {
+		return fmt.Errorf("decoded unexpected type %v from --config %q", gvk, o.ConfigFile)
+	}
+	// Fill in any field the file itself left unset before merging, so a
+	// partial file behaves the same as unset flags.
+	scheme.Default(cfg)
+
+	o.mergeConfig(cfg, flags)
+	return nil
+}
+
+// mergeConfig copies non-zero fields from cfg onto o, skipping any field
+// whose corresponding flag was explicitly set on the command line.
+func (o *ProxyRunOptions) mergeConfig(cfg *v1alpha1.ProxyServerConfiguration, flags *pflag.FlagSet) {
+	set := func(name string) bool { return flags != nil && flags.Changed(name) }
+
+	if !set("server-cert") && cfg.Server.Cert != "" {
+		o.ServerCert = cfg.Server.Cert
+	}
+	if !set("server-key") && cfg.Server.Key != "" {
+		o.ServerKey = cfg.Server.Key
+	}
+	if !set("server-ca-cert") && cfg.Server.CACert != "" {
+		o.ServerCaCert = cfg.Server.CACert
+	}
+	if !set("cluster-cert") && cfg.Cluster.Cert != "" {
+		o.ClusterCert = cfg.Cluster.Cert
+	}
+	if !set("cluster-key") && cfg.Cluster.Key != "" {
+		o.ClusterKey = cfg.Cluster.Key
+	}
+	if !set("cluster-ca-cert") && cfg.Cluster.CACert != "" {
+		o.ClusterCaCert = cfg.Cluster.CACert
+	}
+	if !set("mode") && cfg.Mode != "" {
+		o.Mode = cfg.Mode
+	}
+	if !set("uds-name") && cfg.UDSName != "" {
+		o.UdsName = cfg.UDSName
+	}
+	// DeleteUDSFile defaults to true, so unlike the other bool fields above,
+	// its zero value ("false") is a real, commonly desired setting rather
+	// than a proxy for "unset" — hence the pointer, checked for nil instead
+	// of for the Go zero value.
+	if !set("delete-existing-uds-file") && cfg.DeleteUDSFile != nil {
+		o.DeleteUDSFile = *cfg.DeleteUDSFile
+	}
+	if !set("server-port") && cfg.ServerPort != 0 {
+		o.ServerPort = cfg.ServerPort
+	}
+	if !set("server-bind-address") && cfg.ServerBindAddress != "" {
+		o.ServerBindAddress = cfg.ServerBindAddress
+	}
+	if !set("agent-port") && cfg.AgentPort != 0 {
+		o.AgentPort = cfg.AgentPort
+	}
+	if !set("agent-bind-address") && cfg.AgentBindAddress != "" {
+		o.AgentBindAddress = cfg.AgentBindAddress
+	}
+	if !set("admin-port") && cfg.AdminPort != 0 {
+		o.AdminPort = cfg.AdminPort
+	}
+	if !set("admin-bind-address") && cfg.AdminBindAddress != "" {
+		o.AdminBindAddress = cfg.AdminBindAddress
+	}
+	if !set("health-port") && cfg.HealthPort != 0 {
+		o.HealthPort = cfg.HealthPort
+	}
+	if !set("health-bind-address") && cfg.HealthBindAddress != "" {
+		o.HealthBindAddress = cfg.HealthBindAddress
+	}
+	if !set("keepalive-time") && cfg.KeepaliveTime.Duration != 0 {
+		o.KeepaliveTime = cfg.KeepaliveTime.Duration
+	}
+	if !set("frontend-keepalive-time") && cfg.FrontendKeepaliveTime.Duration != 0 {
+		o.FrontendKeepaliveTime = cfg.FrontendKeepaliveTime.Duration
+	}
+	if !set("enable-profiling") && cfg.EnableProfiling {
+		o.EnableProfiling = cfg.EnableProfiling
+	}
+	if !set("enable-contention-profiling") && cfg.EnableContentionProfiling {
+		o.EnableContentionProfiling = cfg.EnableContentionProfiling
+	}
+	if !set("server-id") && cfg.ServerID != "" {
+		o.ServerID = cfg.ServerID
+	}
+	if !set("server-count") && cfg.ServerCount != 0 {
+		o.ServerCount = cfg.ServerCount
+	}
+	if !set("proxy-strategies") && cfg.ProxyStrategies != "" {
+		o.ProxyStrategies = cfg.ProxyStrategies
+	}
+	if !set("cipher-suites") && len(cfg.CipherSuites) != 0 {
+		o.CipherSuites = cfg.CipherSuites
+	}
+	if !set("xfr-channel-size") && cfg.XfrChannelSize != 0 {
+		o.XfrChannelSize = cfg.XfrChannelSize
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"testing"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/apis/proxyserver/v1alpha1"
+)
+
+func TestMergeConfigFlagPrecedence(t *testing.T) {
+	o := NewProxyRunOptions()
+	flags := o.Flags()
+	if err := flags.Set("server-port", "9999"); err != nil {
+		t.Fatalf("failed to set server-port: %v", err)
+	}
+
+	cfg := &v1alpha1.ProxyServerConfiguration{
+		ServerPort:        8888,
+		AgentPort:         8891,
+		ServerBindAddress: "10.0.0.1",
+	}
+
+	o.mergeConfig(cfg, flags)
+
+	if o.ServerPort != 9999 {
+		t.Errorf("ServerPort = %d, want 9999 (explicit flag should win over file)", o.ServerPort)
+	}
+	if o.AgentPort != 8891 {
+		t.Errorf("AgentPort = %d, want 8891 (unset flag should take the file value)", o.AgentPort)
+	}
+	if o.ServerBindAddress != "10.0.0.1" {
+		t.Errorf("ServerBindAddress = %q, want %q (unset flag should take the file value)", o.ServerBindAddress, "10.0.0.1")
+	}
+}
+
+func TestMergeConfigLeavesZeroFileValuesAlone(t *testing.T) {
+	o := NewProxyRunOptions()
+	o.AgentPort = 1234
+	flags := o.Flags()
+
+	// An empty/zero field in the file must not clobber an existing value,
+	// since we can't distinguish "unset" from "explicitly zero" in the
+	// versioned config.
+	cfg := &v1alpha1.ProxyServerConfiguration{}
+	o.mergeConfig(cfg, flags)
+
+	if o.AgentPort != 1234 {
+		t.Errorf("AgentPort = %d, want unchanged 1234", o.AgentPort)
+	}
+}
+
+func TestMergeConfigDeleteUDSFileFalseIsHonored(t *testing.T) {
+	o := NewProxyRunOptions()
+	if !o.DeleteUDSFile {
+		t.Fatal("DeleteUDSFile should default to true")
+	}
+	flags := o.Flags()
+
+	deleteUDSFile := false
+	cfg := &v1alpha1.ProxyServerConfiguration{DeleteUDSFile: &deleteUDSFile}
+	o.mergeConfig(cfg, flags)
+
+	if o.DeleteUDSFile {
+		t.Error("DeleteUDSFile = true, want false (an explicit \"false\" in the file must override the flag default)")
+	}
+}
+
+func TestMergeConfigDeleteUDSFileAbsentLeavesDefaultAlone(t *testing.T) {
+	o := NewProxyRunOptions()
+	flags := o.Flags()
+
+	cfg := &v1alpha1.ProxyServerConfiguration{}
+	o.mergeConfig(cfg, flags)
+
+	if !o.DeleteUDSFile {
+		t.Error("DeleteUDSFile = false, want true (unset in the file should leave the flag default alone)")
+	}
+}
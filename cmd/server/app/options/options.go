@@ -17,6 +17,7 @@ limitations under the License.
 package options
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 	"time"
@@ -27,11 +28,23 @@ import (
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/apiserver-network-proxy/pkg/server"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/authz"
 	"sigs.k8s.io/apiserver-network-proxy/pkg/server/proxystrategies"
 	"sigs.k8s.io/apiserver-network-proxy/pkg/util"
 )
 
 type ProxyRunOptions struct {
+	// Path to a versioned ProxyServerConfiguration file (YAML or JSON). If
+	// set, the file is loaded and applied before flags, so flags always take
+	// precedence over the file.
+	ConfigFile string
+	// If true, generate ephemeral self-signed server/cluster certs under
+	// CertDirectory for any of ServerCert/ServerKey/ClusterCert/ClusterKey
+	// left unset. Has no effect when UDS is in use.
+	GenerateSelfSignedCerts bool
+	// Directory in which self-signed certs are written/read when
+	// GenerateSelfSignedCerts is set.
+	CertDirectory string
 	// Certificate setup for securing communication to the "client" i.e. the Kube API Server.
 	ServerCert   string
 	ServerKey    string
@@ -58,6 +71,13 @@ type ProxyRunOptions struct {
 	AdminPort int
 	// Bind address for the admin connections.
 	AdminBindAddress string
+	// Port we serve Prometheus metrics and /flagz on, if different from the
+	// admin listener. Set to 0 to serve metrics on the admin listener
+	// instead of a dedicated one.
+	MetricsPort int
+	// Bind address for the metrics listener. Only used if MetricsPort is
+	// non-zero.
+	MetricsBindAddress string
 	// Port we listen for health connections on.
 	HealthPort int
 	// Bind address for the health connections.
@@ -104,7 +124,13 @@ type ProxyRunOptions struct {
 	// also checks if given comma separated list contains cipher from tls.InsecureCipherSuites().
 	// NOTE that cipher suites are not configurable for TLS1.3,
 	// see: https://pkg.go.dev/crypto/tls#Config, so in that case, this option won't have any effect.
-	CipherSuites   []string
+	CipherSuites []string
+
+	// Minimum TLS version accepted by the frontend and agent listeners,
+	// "1.2" or "1.3". CipherSuites above only restricts TLS1.2 handshakes;
+	// this restricts both.
+	TLSMinVersion string
+
 	XfrChannelSize int
 
 	// Lease controller configuration
@@ -115,16 +141,41 @@ type ProxyRunOptions struct {
 	LeaseLabel string
 	// Needs kubernetes client
 	NeedsKubernetesClient bool
+
+	// Path to a YAML file describing CIDR- and label-based rules for which
+	// dial targets an authenticated agent may serve. If empty, any
+	// authenticated agent may serve any target, as today.
+	AgentAuthorizationConfig string
+
+	// If true, install a SIGHUP handler and an fsnotify watcher on the cert
+	// files and --config that re-read certs/ProxyStrategies/CipherSuites
+	// and rebuild the frontend/agent TLS config without dropping existing
+	// tunnels.
+	EnableConfigReload bool
+
+	// DEV ONLY. If true, serve the "http-connect" frontend listener and the
+	// agent listener by dialing destinations directly from this process,
+	// bypassing agent tunnels entirely. This tree doesn't contain the real
+	// agent tunnel protocol or backend-selection code (pkg/server), so with
+	// this left false (the default) neither listener is started. Do not set
+	// this in any environment where the destination network is only
+	// reachable through an agent: it silently turns "http-connect" into a
+	// same-network-only direct proxy and never authorizes dials against a
+	// real agent identity.
+	DevDirectDialMode bool
 }
 
 func (o *ProxyRunOptions) Flags() *pflag.FlagSet {
 	flags := pflag.NewFlagSet("proxy-server", pflag.ContinueOnError)
+	flags.StringVar(&o.ConfigFile, "config", o.ConfigFile, "Path to a versioned ProxyServerConfiguration file (YAML or JSON). Values from the file are applied first, flags override them.")
 	flags.StringVar(&o.ServerCert, "server-cert", o.ServerCert, "If non-empty secure communication with this cert.")
 	flags.StringVar(&o.ServerKey, "server-key", o.ServerKey, "If non-empty secure communication with this key.")
 	flags.StringVar(&o.ServerCaCert, "server-ca-cert", o.ServerCaCert, "If non-empty the CA we use to validate KAS clients.")
 	flags.StringVar(&o.ClusterCert, "cluster-cert", o.ClusterCert, "If non-empty secure communication with this cert.")
 	flags.StringVar(&o.ClusterKey, "cluster-key", o.ClusterKey, "If non-empty secure communication with this key.")
 	flags.StringVar(&o.ClusterCaCert, "cluster-ca-cert", o.ClusterCaCert, "If non-empty the CA we use to validate Agent clients.")
+	flags.BoolVar(&o.GenerateSelfSignedCerts, "generate-self-signed-certs", o.GenerateSelfSignedCerts, "If true, generate a self-signed server/cluster cert under --cert-directory for any of server-cert, server-key, cluster-cert, cluster-key left unset. Useful for local dev, e2e tests, and demos. Has no effect when using UDS.")
+	flags.StringVar(&o.CertDirectory, "cert-directory", o.CertDirectory, "Directory in which self-signed certs are written/read when --generate-self-signed-certs is set.")
 	flags.StringVar(&o.Mode, "mode", o.Mode, "mode can be either 'grpc' or 'http-connect'.")
 	flags.StringVar(&o.UdsName, "uds-name", o.UdsName, "uds-name should be empty for TCP traffic. For UDS set to its name.")
 	flags.BoolVar(&o.DeleteUDSFile, "delete-existing-uds-file", o.DeleteUDSFile, "If true and if file UdsName already exists, delete the file before listen on that UDS file. Default is true.")
@@ -134,6 +185,8 @@ func (o *ProxyRunOptions) Flags() *pflag.FlagSet {
 	flags.StringVar(&o.AgentBindAddress, "agent-bind-address", o.AgentBindAddress, "Bind address for agent connections. If empty, we will bind to all interfaces.")
 	flags.IntVar(&o.AdminPort, "admin-port", o.AdminPort, "Port we listen for admin connections on.")
 	flags.StringVar(&o.AdminBindAddress, "admin-bind-address", o.AdminBindAddress, "Bind address for admin connections. If empty, we will bind to localhost.")
+	flags.IntVar(&o.MetricsPort, "metrics-port", o.MetricsPort, "Port we serve Prometheus metrics and /flagz on. If 0, metrics are served on the admin listener instead of a dedicated one.")
+	flags.StringVar(&o.MetricsBindAddress, "metrics-bind-address", o.MetricsBindAddress, "Bind address for the metrics listener. Only used if metrics-port is non-zero. If empty, we will bind to localhost.")
 	flags.IntVar(&o.HealthPort, "health-port", o.HealthPort, "Port we listen for health connections on.")
 	flags.StringVar(&o.HealthBindAddress, "health-bind-address", o.HealthBindAddress, "Bind address for health connections. If empty, we will bind to all interfaces.")
 	flags.DurationVar(&o.KeepaliveTime, "keepalive-time", o.KeepaliveTime, "Time for gRPC agent server keepalive.")
@@ -151,10 +204,14 @@ func (o *ProxyRunOptions) Flags() *pflag.FlagSet {
 	flags.StringVar(&o.AuthenticationAudience, "authentication-audience", o.AuthenticationAudience, "Expected agent's token authentication audience (used with agent-namespace, agent-service-account, kubeconfig).")
 	flags.StringVar(&o.ProxyStrategies, "proxy-strategies", o.ProxyStrategies, "The list of proxy strategies used by the server to pick an agent/tunnel, available strategies are: default, destHost, defaultRoute.")
 	flags.StringSliceVar(&o.CipherSuites, "cipher-suites", o.CipherSuites, "The comma separated list of allowed cipher suites. Has no effect on TLS1.3. Empty means allow default list.")
+	flags.StringVar(&o.TLSMinVersion, "tls-min-version", o.TLSMinVersion, "Minimum TLS version accepted by the frontend and agent listeners: \"1.2\" or \"1.3\".")
 	flags.IntVar(&o.XfrChannelSize, "xfr-channel-size", o.XfrChannelSize, "The size of the two KNP server channels used in server for transferring data. One channel is for data coming from the Kubernetes API Server, and the other one is for data coming from the KNP agent.")
 	flags.BoolVar(&o.EnableLeaseController, "enable-lease-controller", o.EnableLeaseController, "Enable lease controller to publish and garbage collect proxy server leases.")
 	flags.StringVar(&o.LeaseNamespace, "lease-namespace", o.LeaseNamespace, "The namespace where lease objects are managed by the controller.")
 	flags.StringVar(&o.LeaseLabel, "lease-label", o.LeaseLabel, "The labels on which the lease objects are managed.")
+	flags.StringVar(&o.AgentAuthorizationConfig, "agent-authorization-config", o.AgentAuthorizationConfig, "Path to a YAML file of CIDR- and label-based rules restricting which dial targets an authenticated agent may serve. If empty, any authenticated agent may serve any target.")
+	flags.BoolVar(&o.EnableConfigReload, "enable-config-reload", o.EnableConfigReload, "If true, watch server-cert/server-key/cluster-cert/cluster-key and --config for changes (or a SIGHUP) and reload certs, cipher suites, and proxy strategies without dropping existing tunnels.")
+	flags.BoolVar(&o.DevDirectDialMode, "dev-direct-dial-mode", o.DevDirectDialMode, "DEV ONLY, do not use in production. If true, serve the http-connect frontend and agent listeners by dialing destinations directly, bypassing agent tunnels. Defaults to false, which disables both listeners, since this tree does not contain the real agent tunnel protocol.")
 	flags.Bool("warn-on-channel-limit", true, "This behavior is now thread safe and always on. This flag will be removed in a future release.")
 	flags.MarkDeprecated("warn-on-channel-limit", "This behavior is now thread safe and always on. This flag will be removed in a future release.")
 
@@ -162,12 +219,15 @@ func (o *ProxyRunOptions) Flags() *pflag.FlagSet {
 }
 
 func (o *ProxyRunOptions) Print() {
+	klog.V(1).Infof("ConfigFile set to %q.\n", o.ConfigFile)
 	klog.V(1).Infof("ServerCert set to %q.\n", o.ServerCert)
 	klog.V(1).Infof("ServerKey set to %q.\n", o.ServerKey)
 	klog.V(1).Infof("ServerCACert set to %q.\n", o.ServerCaCert)
 	klog.V(1).Infof("ClusterCert set to %q.\n", o.ClusterCert)
 	klog.V(1).Infof("ClusterKey set to %q.\n", o.ClusterKey)
 	klog.V(1).Infof("ClusterCACert set to %q.\n", o.ClusterCaCert)
+	klog.V(1).Infof("GenerateSelfSignedCerts set to %v.\n", o.GenerateSelfSignedCerts)
+	klog.V(1).Infof("CertDirectory set to %q.\n", o.CertDirectory)
 	klog.V(1).Infof("Mode set to %q.\n", o.Mode)
 	klog.V(1).Infof("UDSName set to %q.\n", o.UdsName)
 	klog.V(1).Infof("DeleteUDSFile set to %v.\n", o.DeleteUDSFile)
@@ -177,6 +237,8 @@ func (o *ProxyRunOptions) Print() {
 	klog.V(1).Infof("Agent bind address set to %q.\n", o.AgentBindAddress)
 	klog.V(1).Infof("Admin port set to %d.\n", o.AdminPort)
 	klog.V(1).Infof("Admin bind address set to %q.\n", o.AdminBindAddress)
+	klog.V(1).Infof("Metrics port set to %d.\n", o.MetricsPort)
+	klog.V(1).Infof("Metrics bind address set to %q.\n", o.MetricsBindAddress)
 	klog.V(1).Infof("Health port set to %d.\n", o.HealthPort)
 	klog.V(1).Infof("Health bind address set to %q.\n", o.HealthBindAddress)
 	klog.V(1).Infof("Keepalive time set to %v.\n", o.KeepaliveTime)
@@ -196,10 +258,20 @@ func (o *ProxyRunOptions) Print() {
 	klog.V(1).Infof("EnableLeaseController set to %v.\n", o.EnableLeaseController)
 	klog.V(1).Infof("LeaseNamespace set to %s.\n", o.LeaseNamespace)
 	klog.V(1).Infof("LeaseLabel set to %s.\n", o.LeaseLabel)
+	klog.V(1).Infof("AgentAuthorizationConfig set to %q.\n", o.AgentAuthorizationConfig)
+	klog.V(1).Infof("EnableConfigReload set to %v.\n", o.EnableConfigReload)
+	klog.V(1).Infof("DevDirectDialMode set to %v.\n", o.DevDirectDialMode)
 	klog.V(1).Infof("CipherSuites set to %q.\n", o.CipherSuites)
+	klog.V(1).Infof("TLSMinVersion set to %q.\n", o.TLSMinVersion)
 	klog.V(1).Infof("XfrChannelSize set to %d.\n", o.XfrChannelSize)
 }
 
+// Validate checks the options for consistency and correctness. It should be
+// called after flags.Parse() and, if --config is set, after
+// ApplyConfigFromFile has merged the loaded file into o, so that validation
+// sees the fully-merged configuration. If --generate-self-signed-certs is
+// set, MaybeGenerateSelfSignedCerts should also run first so its generated
+// paths are what gets validated below.
 func (o *ProxyRunOptions) Validate() error {
 	if o.ServerKey != "" {
 		if _, err := os.Stat(o.ServerKey); os.IsNotExist(err) {
@@ -269,6 +341,9 @@ func (o *ProxyRunOptions) Validate() error {
 	if o.AdminPort > 49151 {
 		return fmt.Errorf("please do not try to use ephemeral port %d for the admin port", o.AdminPort)
 	}
+	if o.MetricsPort != 0 && o.MetricsPort > 49151 {
+		return fmt.Errorf("please do not try to use ephemeral port %d for the metrics port", o.MetricsPort)
+	}
 	if o.HealthPort > 49151 {
 		return fmt.Errorf("please do not try to use ephemeral port %d for the health port", o.HealthPort)
 	}
@@ -284,6 +359,9 @@ func (o *ProxyRunOptions) Validate() error {
 	if o.AdminPort < 1024 {
 		return fmt.Errorf("please do not try to use reserved port %d for the admin port", o.AdminPort)
 	}
+	if o.MetricsPort != 0 && o.MetricsPort < 1024 {
+		return fmt.Errorf("please do not try to use reserved port %d for the metrics port", o.MetricsPort)
+	}
 	if o.HealthPort < 1024 {
 		return fmt.Errorf("please do not try to use reserved port %d for the health port", o.HealthPort)
 	}
@@ -331,6 +409,9 @@ func (o *ProxyRunOptions) Validate() error {
 			}
 		}
 	}
+	if _, err := o.MinTLSVersion(); err != nil {
+		return err
+	}
 	// Validate labels provided.
 	if o.EnableLeaseController {
 		_, err := util.ParseLabels(o.LeaseLabel)
@@ -338,14 +419,77 @@ func (o *ProxyRunOptions) Validate() error {
 			return err
 		}
 	}
+	// Validate the agent authorization config, if any, rejecting malformed
+	// CIDRs up front rather than at dial time.
+	if o.AgentAuthorizationConfig != "" {
+		if _, err := o.loadAgentAuthorizationConfig(); err != nil {
+			return err
+		}
+	}
+	if o.EnableConfigReload && o.ServerCert == "" && o.ClusterCert == "" && o.ConfigFile == "" {
+		return fmt.Errorf("--enable-config-reload has nothing to watch: set --server-cert, --cluster-cert, or --config")
+	}
+	if o.DevDirectDialMode && o.AgentAuthorizationConfig != "" {
+		return fmt.Errorf("--agent-authorization-config cannot be used with --dev-direct-dial-mode: the http-connect listener it runs authorizes the Kube API Server's own connection, not an agent, so a ServiceAccount/NodeCIDR/Labels rule can never match the agent it was written for")
+	}
 
 	o.NeedsKubernetesClient = usingServiceAccountAuth || o.EnableLeaseController
 
 	return nil
 }
 
+// MinTLSVersion parses --tls-min-version into a tls.Config-compatible
+// version constant. Empty means unset, in which case the caller should
+// leave tls.Config's MinVersion at its zero value (the stdlib default,
+// currently TLS1.2).
+func (o *ProxyRunOptions) MinTLSVersion() (uint16, error) {
+	switch o.TLSMinVersion {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("tls-min-version must be \"1.2\" or \"1.3\", not %q", o.TLSMinVersion)
+	}
+}
+
+// loadAgentAuthorizationConfig reads and validates --agent-authorization-config.
+// It is called both from Validate (to fail fast on a malformed file) and
+// from Authorizer (to build the Authorizer actually enforced at dial time),
+// so the two can never disagree about whether the file is valid.
+func (o *ProxyRunOptions) loadAgentAuthorizationConfig() (*authz.Config, error) {
+	cfg, err := authz.LoadConfig(o.AgentAuthorizationConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.Validate(cfg); err != nil {
+		return nil, fmt.Errorf("invalid --agent-authorization-config: %v", err)
+	}
+	return cfg, nil
+}
+
+// Authorizer builds the Authorizer to enforce for dials, per
+// --agent-authorization-config. It returns authz.AlwaysAllowAuthorizer if
+// the flag is unset, matching today's behavior of trusting any
+// authenticated agent.
+func (o *ProxyRunOptions) Authorizer() (authz.Authorizer, error) {
+	if o.AgentAuthorizationConfig == "" {
+		return authz.AlwaysAllowAuthorizer, nil
+	}
+	cfg, err := o.loadAgentAuthorizationConfig()
+	if err != nil {
+		return nil, err
+	}
+	return authz.NewAuthorizer(cfg), nil
+}
+
 func NewProxyRunOptions() *ProxyRunOptions {
 	o := ProxyRunOptions{
+		ConfigFile:                "",
+		GenerateSelfSignedCerts:   false,
+		CertDirectory:             "/var/run/kubernetes",
 		ServerCert:                "",
 		ServerKey:                 "",
 		ServerCaCert:              "",
@@ -363,6 +507,8 @@ func NewProxyRunOptions() *ProxyRunOptions {
 		HealthBindAddress:         "",
 		AdminPort:                 8095,
 		AdminBindAddress:          "127.0.0.1",
+		MetricsPort:               0,
+		MetricsBindAddress:        "127.0.0.1",
 		KeepaliveTime:             1 * time.Hour,
 		FrontendKeepaliveTime:     1 * time.Hour,
 		EnableProfiling:           false,
@@ -378,14 +524,32 @@ func NewProxyRunOptions() *ProxyRunOptions {
 		AuthenticationAudience:    "",
 		ProxyStrategies:           "default",
 		CipherSuites:              make([]string, 0),
+		TLSMinVersion:             "1.2",
 		XfrChannelSize:            10,
 		EnableLeaseController:     false,
 		LeaseNamespace:            "kube-system",
 		LeaseLabel:                "k8s-app=konnectivity-server",
+		AgentAuthorizationConfig:  "",
+		EnableConfigReload:        false,
+		DevDirectDialMode:         false,
 	}
 	return &o
 }
 
+// Redacted returns a copy of o with secret-bearing fields (private key
+// paths) replaced by a placeholder, suitable for exposing over /configz.
+func (o *ProxyRunOptions) Redacted() *ProxyRunOptions {
+	redacted := *o
+	const placeholder = "<redacted>"
+	if redacted.ServerKey != "" {
+		redacted.ServerKey = placeholder
+	}
+	if redacted.ClusterKey != "" {
+		redacted.ClusterKey = placeholder
+	}
+	return &redacted
+}
+
 func defaultServerID() string {
 	// Default to the value set by the PROXY_SERVER_ID environment variable. If both the flag &
 	// environment variable are set, the flag always wins.
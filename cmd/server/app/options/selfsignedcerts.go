@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+)
+
+// MaybeGenerateSelfSignedCerts materializes ephemeral server/cluster certs
+// into o.CertDirectory when o.GenerateSelfSignedCerts is set and the
+// corresponding cert/key flags were left empty, mirroring kubelet's
+// InitializeTLS. It is a no-op for UDS, which doesn't use TLS. Existing,
+// readable certs in the cert directory are reused rather than regenerated,
+// so restarts don't churn the agent's trust store.
+//
+// Callers should invoke this after flags.Parse() (and after
+// ApplyConfigFromFile, if used) and before Validate(), so that Validate's
+// file-existence checks see the generated paths.
+func (o *ProxyRunOptions) MaybeGenerateSelfSignedCerts() error {
+	if !o.GenerateSelfSignedCerts {
+		return nil
+	}
+	if o.UdsName != "" {
+		return nil
+	}
+
+	if err := generateSelfSignedCertIfNeeded(o.CertDirectory, "proxy-server", &o.ServerCert, &o.ServerKey, o.ServerBindAddress); err != nil {
+		return fmt.Errorf("failed to generate self-signed server cert: %v", err)
+	}
+	if err := generateSelfSignedCertIfNeeded(o.CertDirectory, "proxy-agent", &o.ClusterCert, &o.ClusterKey, o.AgentBindAddress); err != nil {
+		return fmt.Errorf("failed to generate self-signed cluster cert: %v", err)
+	}
+	return nil
+}
+
+// generateSelfSignedCertIfNeeded fills in *certPath/*keyPath with
+// prefix-named files under certDir, generating a new self-signed cert/key
+// pair there if one isn't already present and readable. It is a no-op if
+// either path is already set.
+func generateSelfSignedCertIfNeeded(certDir, prefix string, certPath, keyPath *string, bindAddress string) error {
+	if *certPath != "" || *keyPath != "" {
+		return nil
+	}
+
+	generatedCertPath := filepath.Join(certDir, prefix+".crt")
+	generatedKeyPath := filepath.Join(certDir, prefix+".key")
+
+	canReadCertAndKey, err := certutil.CanReadCertAndKey(generatedCertPath, generatedKeyPath)
+	if err != nil {
+		return err
+	}
+	if !canReadCertAndKey {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get hostname: %v", err)
+		}
+
+		altNames := certutil.AltNames{DNSNames: []string{hostname, "localhost"}}
+		for _, addr := range []string{bindAddress} {
+			if addr == "" {
+				continue
+			}
+			if ip := net.ParseIP(addr); ip != nil {
+				altNames.IPs = append(altNames.IPs, ip)
+			} else {
+				altNames.DNSNames = append(altNames.DNSNames, addr)
+			}
+		}
+
+		if err := os.MkdirAll(certDir, 0755); err != nil {
+			return fmt.Errorf("unable to create cert directory %q: %v", certDir, err)
+		}
+		cert, key, err := certutil.GenerateSelfSignedCertKeyWithFixtures(hostname, altNames.IPs, altNames.DNSNames, "")
+		if err != nil {
+			return fmt.Errorf("unable to generate self signed cert: %v", err)
+		}
+		if err := certutil.WriteCert(generatedCertPath, cert); err != nil {
+			return err
+		}
+		if err := keyutil.WriteKey(generatedKeyPath, key); err != nil {
+			return err
+		}
+		klog.Infof("Generated self-signed cert (%s, %s)", generatedCertPath, generatedKeyPath)
+	} else {
+		klog.V(1).Infof("Using existing self-signed cert (%s, %s)", generatedCertPath, generatedKeyPath)
+	}
+
+	*certPath = generatedCertPath
+	*keyPath = generatedKeyPath
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMaybeGenerateSelfSignedCertsSkippedByDefault(t *testing.T) {
+	o := NewProxyRunOptions()
+	o.CertDirectory = t.TempDir()
+
+	if err := o.MaybeGenerateSelfSignedCerts(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.ServerCert != "" || o.ServerKey != "" {
+		t.Errorf("expected no certs generated when GenerateSelfSignedCerts is false, got %q/%q", o.ServerCert, o.ServerKey)
+	}
+}
+
+func TestMaybeGenerateSelfSignedCertsSkippedForUDS(t *testing.T) {
+	o := NewProxyRunOptions()
+	o.GenerateSelfSignedCerts = true
+	o.UdsName = "/tmp/proxy.sock"
+	o.CertDirectory = t.TempDir()
+
+	if err := o.MaybeGenerateSelfSignedCerts(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.ServerCert != "" {
+		t.Errorf("expected no cert generated for UDS, got %q", o.ServerCert)
+	}
+}
+
+func TestMaybeGenerateSelfSignedCertsGeneratesAndReuses(t *testing.T) {
+	o := NewProxyRunOptions()
+	o.GenerateSelfSignedCerts = true
+	o.CertDirectory = t.TempDir()
+
+	if err := o.MaybeGenerateSelfSignedCerts(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantCert := filepath.Join(o.CertDirectory, "proxy-server.crt")
+	wantKey := filepath.Join(o.CertDirectory, "proxy-server.key")
+	if o.ServerCert != wantCert || o.ServerKey != wantKey {
+		t.Fatalf("ServerCert/ServerKey = %q/%q, want %q/%q", o.ServerCert, o.ServerKey, wantCert, wantKey)
+	}
+	info, err := os.Stat(wantCert)
+	if err != nil {
+		t.Fatalf("expected generated cert file, got error: %v", err)
+	}
+	firstModTime := info.ModTime()
+
+	// A second run with the same (now populated) cert/key fields is a
+	// no-op: generateSelfSignedCertIfNeeded only fires when both are empty.
+	o.ServerCert = ""
+	o.ServerKey = ""
+	if err := o.MaybeGenerateSelfSignedCerts(); err != nil {
+		t.Fatalf("unexpected error on second run: %v", err)
+	}
+	info, err = os.Stat(wantCert)
+	if err != nil {
+		t.Fatalf("expected cert file to still exist: %v", err)
+	}
+	if !info.ModTime().Equal(firstModTime) {
+		t.Errorf("expected existing readable cert to be reused, but it was regenerated")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/tls"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/cmd/server/app/options"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/certreload"
+)
+
+// maybeFrontendTLSConfig builds the tls.Config for the frontend (Kube API
+// Server-facing) listener from o.ServerCert/ServerKey, returning the
+// CertReloader backing it so it can be kept fresh by
+// runConfigReloadWatcher. Returns a nil config (and reloader) when no
+// server cert is configured, leaving the frontend listener as plain TCP.
+//
+// MinVersion is fixed at startup (--tls-min-version doesn't participate in
+// --enable-config-reload), but CipherSuites is read fresh from strategies
+// on every handshake via GetConfigForClient, so a --cipher-suites reload
+// takes effect without restarting the listener.
+func maybeFrontendTLSConfig(o *options.ProxyRunOptions, strategies *certreload.StrategyReloader) (*tls.Config, *certreload.CertReloader, error) {
+	if o.ServerCert == "" {
+		return nil, nil, nil
+	}
+	reloader, err := certreload.NewCertReloader(o.ServerCert, o.ServerKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	minVersion, err := o.MinTLSVersion()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				GetCertificate: reloader.GetCertificate,
+				MinVersion:     minVersion,
+				CipherSuites:   strategies.CipherSuiteIDs(),
+			}, nil
+		},
+	}, reloader, nil
+}
+
+// maybeAgentTLSConfig is the agent-tunnel-listener equivalent of
+// maybeFrontendTLSConfig, built from o.ClusterCert/ClusterKey.
+func maybeAgentTLSConfig(o *options.ProxyRunOptions, strategies *certreload.StrategyReloader) (*tls.Config, *certreload.CertReloader, error) {
+	if o.ClusterCert == "" {
+		return nil, nil, nil
+	}
+	reloader, err := certreload.NewCertReloader(o.ClusterCert, o.ClusterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	minVersion, err := o.MinTLSVersion()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				GetCertificate: reloader.GetCertificate,
+				MinVersion:     minVersion,
+				CipherSuites:   strategies.CipherSuiteIDs(),
+			}, nil
+		},
+	}, reloader, nil
+}
+
+// runConfigReloadWatcher starts the --enable-config-reload watcher, which
+// reloads every cert reloader in reloaders (nil entries are skipped) and
+// strategies on SIGHUP or whenever --server-cert/--server-key/
+// --cluster-cert/--cluster-key/--config changes on disk. It runs until the
+// process exits; stopCh is never closed today since runServer itself
+// blocks until a listener fails.
+func runConfigReloadWatcher(o *options.ProxyRunOptions, reloaders []*certreload.CertReloader, strategies *certreload.StrategyReloader, stopCh <-chan struct{}) error {
+	if !o.EnableConfigReload {
+		return nil
+	}
+
+	onReload := func() error {
+		for _, r := range reloaders {
+			if r == nil {
+				continue
+			}
+			if err := r.Reload(); err != nil {
+				return err
+			}
+		}
+		// Re-parsing CipherSuites here takes effect immediately: the
+		// frontend/agent TLS listeners read strategies.CipherSuiteIDs()
+		// fresh on every handshake (see maybeFrontendTLSConfig). Re-parsing
+		// ProxyStrategies, on the other hand, only takes effect once the
+		// backend-selection path that would consult
+		// strategies.ProxyStrategies() exists — that's pkg/server, which
+		// this tree doesn't contain.
+		return strategies.Reload(o.ProxyStrategies, o.CipherSuites)
+	}
+
+	watcher, err := certreload.NewWatcher(onReload, o.ServerCert, o.ServerKey, o.ClusterCert, o.ClusterKey, o.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		klog.Info("watching for SIGHUP and cert/config file changes to reload")
+		watcher.Run(stopCh)
+	}()
+
+	return nil
+}
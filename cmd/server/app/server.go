@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package app wires a ProxyRunOptions into a running proxy server: it is
+// the composition root invoked by cmd/server/main.go.
+package app
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/cmd/server/app/options"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/certreload"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+// NewProxyServerCommand builds the proxy-server cobra command: it binds
+// ProxyRunOptions' flags and, on Execute, loads --config (if any), merges
+// flags on top, validates, and runs the server.
+func NewProxyServerCommand() *cobra.Command {
+	o := options.NewProxyRunOptions()
+
+	cmd := &cobra.Command{
+		Use:  "proxy-server",
+		Long: "proxy-server is the Konnectivity network proxy server.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(o, cmd.Flags())
+		},
+	}
+	cmd.Flags().AddFlagSet(o.Flags())
+
+	return cmd
+}
+
+// Run applies o.ConfigFile (if set), validates the merged options, and
+// starts the server. flags is the parsed flag set, used to tell which
+// values were explicitly set on the command line so --config doesn't
+// clobber them.
+func Run(o *options.ProxyRunOptions, flags *pflag.FlagSet) error {
+	if err := o.ApplyConfigFromFile(flags); err != nil {
+		return err
+	}
+	if err := o.MaybeGenerateSelfSignedCerts(); err != nil {
+		return err
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	o.Print()
+
+	klog.Infof("starting proxy-server %s", o.ServerID)
+	return runServer(o, flags)
+}
+
+// runServer starts the admin, metrics, frontend, and agent listeners (plus
+// the --enable-config-reload watcher, if set) and blocks until one of the
+// listeners fails.
+func runServer(o *options.ProxyRunOptions, flags *pflag.FlagSet) error {
+	errCh := make(chan error, 1)
+	serverMetrics := metrics.NewServerMetrics()
+
+	if err := runAdminServer(o, flags, serverMetrics.Registry(), errCh); err != nil {
+		return err
+	}
+	if err := runMetricsServer(o, serverMetrics.Registry(), errCh); err != nil {
+		return err
+	}
+
+	strategies, err := certreload.NewStrategyReloader(o.ProxyStrategies, o.CipherSuites)
+	if err != nil {
+		return err
+	}
+	frontendCertReloader, err := runFrontendServer(o, serverMetrics, strategies, errCh)
+	if err != nil {
+		return err
+	}
+	agentCertReloader, err := runAgentListener(o, serverMetrics, strategies, errCh)
+	if err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	reloaders := []*certreload.CertReloader{frontendCertReloader, agentCertReloader}
+	if err := runConfigReloadWatcher(o, reloaders, strategies, stopCh); err != nil {
+		return err
+	}
+
+	return <-errCh
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package install installs the proxyserver API group, mirroring the
+// install packages used by kube-apiserver/kube-scheduler to register their
+// componentconfig types.
+package install
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/apis/proxyserver/v1alpha1"
+)
+
+// Install registers the proxyserver API group and its types into the given
+// scheme.
+func Install(scheme *runtime.Scheme) error {
+	return v1alpha1.AddToScheme(scheme)
+}
+
+// NewScheme returns a new Scheme with the proxyserver API group installed,
+// along with a codec factory that can decode ProxyServerConfiguration
+// objects from YAML or JSON.
+func NewScheme() (*runtime.Scheme, *serializer.CodecFactory, error) {
+	scheme := runtime.NewScheme()
+	if err := Install(scheme); err != nil {
+		return nil, nil, err
+	}
+	codecs := serializer.NewCodecFactory(scheme)
+	return scheme, &codecs, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// RegisterDefaults registers the SetObjectDefaults_* functions with the
+// given scheme, mirroring the generated defaulting code used by
+// componentconfig-style APIs elsewhere in Kubernetes.
+func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&ProxyServerConfiguration{}, func(obj interface{}) {
+		SetDefaults_ProxyServerConfiguration(obj.(*ProxyServerConfiguration))
+	})
+	return nil
+}
+
+// SetDefaults_ProxyServerConfiguration fills in defaults for fields left
+// unset in a loaded configuration file. These match the zero-value
+// defaults used by options.NewProxyRunOptions so that a partial config
+// file behaves the same as unset flags.
+func SetDefaults_ProxyServerConfiguration(obj *ProxyServerConfiguration) {
+	if obj.Mode == "" {
+		obj.Mode = "grpc"
+	}
+	if obj.ServerPort == 0 {
+		obj.ServerPort = 8090
+	}
+	if obj.AgentPort == 0 {
+		obj.AgentPort = 8091
+	}
+	if obj.HealthPort == 0 {
+		obj.HealthPort = 8092
+	}
+	if obj.AdminPort == 0 {
+		obj.AdminPort = 8095
+	}
+	if obj.AdminBindAddress == "" {
+		obj.AdminBindAddress = "127.0.0.1"
+	}
+	if obj.KeepaliveTime.Duration == 0 {
+		obj.KeepaliveTime = metav1.Duration{Duration: time.Hour}
+	}
+	if obj.FrontendKeepaliveTime.Duration == 0 {
+		obj.FrontendKeepaliveTime = metav1.Duration{Duration: time.Hour}
+	}
+	if obj.ServerCount == 0 {
+		obj.ServerCount = 1
+	}
+	if obj.ProxyStrategies == "" {
+		obj.ProxyStrategies = "default"
+	}
+	if obj.XfrChannelSize == 0 {
+		obj.XfrChannelSize = 10
+	}
+}
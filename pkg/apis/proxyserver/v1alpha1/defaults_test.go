@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "testing"
+
+func TestSetDefaults_ProxyServerConfiguration(t *testing.T) {
+	cfg := &ProxyServerConfiguration{}
+	SetDefaults_ProxyServerConfiguration(cfg)
+
+	if cfg.Mode != "grpc" {
+		t.Errorf("Mode = %q, want %q", cfg.Mode, "grpc")
+	}
+	if cfg.ServerPort != 8090 {
+		t.Errorf("ServerPort = %d, want 8090", cfg.ServerPort)
+	}
+	if cfg.AdminBindAddress != "127.0.0.1" {
+		t.Errorf("AdminBindAddress = %q, want %q", cfg.AdminBindAddress, "127.0.0.1")
+	}
+	if cfg.ProxyStrategies != "default" {
+		t.Errorf("ProxyStrategies = %q, want %q", cfg.ProxyStrategies, "default")
+	}
+}
+
+func TestSetDefaults_ProxyServerConfiguration_PreservesSetValues(t *testing.T) {
+	cfg := &ProxyServerConfiguration{
+		Mode:       "http-connect",
+		ServerPort: 9090,
+	}
+	SetDefaults_ProxyServerConfiguration(cfg)
+
+	if cfg.Mode != "http-connect" {
+		t.Errorf("Mode = %q, want unchanged %q", cfg.Mode, "http-connect")
+	}
+	if cfg.ServerPort != 9090 {
+		t.Errorf("ServerPort = %d, want unchanged 9090", cfg.ServerPort)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProxyServerConfiguration is the on-disk representation of the proxy
+// server's configuration. It mirrors cmd/server/app/options.ProxyRunOptions
+// so that settings which don't map cleanly onto flags (multiple proxy
+// strategies, per-listener TLS, cipher-suite groups, agent authorization
+// rules) can be expressed in a single structured file and GitOps'd like
+// any other componentconfig.
+type ProxyServerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Server holds the settings for the "server" listener, i.e. the one used
+	// by the Kube API Server.
+	Server ServerTransportConfiguration `json:"server,omitempty"`
+	// Cluster holds the settings for the "agent" listener, i.e. the one used
+	// by the managed cluster's konnectivity-agent.
+	Cluster ClusterTransportConfiguration `json:"cluster,omitempty"`
+
+	// Mode can be either 'grpc' or 'http-connect'.
+	Mode string `json:"mode,omitempty"`
+	// UDSName, if set, enables UDS for server connections instead of TCP.
+	UDSName string `json:"udsName,omitempty"`
+	// DeleteUDSFile deletes an existing UDS file before listening on it. A
+	// pointer so that an explicit "false" in the file (a real, commonly
+	// desired setting, unlike most other bool fields here) is distinguishable
+	// from the field being left unset; nil defers to the flag default.
+	DeleteUDSFile *bool `json:"deleteUDSFile,omitempty"`
+
+	// ServerPort is the port we listen for server connections on. Set to 0
+	// for UDS.
+	ServerPort int `json:"serverPort,omitempty"`
+	// ServerBindAddress is the bind address for server connections.
+	ServerBindAddress string `json:"serverBindAddress,omitempty"`
+	// AgentPort is the port we listen for agent connections on.
+	AgentPort int `json:"agentPort,omitempty"`
+	// AgentBindAddress is the bind address for agent connections.
+	AgentBindAddress string `json:"agentBindAddress,omitempty"`
+	// AdminPort is the port we listen for admin connections on.
+	AdminPort int `json:"adminPort,omitempty"`
+	// AdminBindAddress is the bind address for admin connections.
+	AdminBindAddress string `json:"adminBindAddress,omitempty"`
+	// HealthPort is the port we listen for health connections on.
+	HealthPort int `json:"healthPort,omitempty"`
+	// HealthBindAddress is the bind address for health connections.
+	HealthBindAddress string `json:"healthBindAddress,omitempty"`
+
+	// KeepaliveTime is the gRPC agent server keepalive time.
+	KeepaliveTime metav1.Duration `json:"keepaliveTime,omitempty"`
+	// FrontendKeepaliveTime is the gRPC frontend server keepalive time.
+	FrontendKeepaliveTime metav1.Duration `json:"frontendKeepaliveTime,omitempty"`
+
+	// EnableProfiling enables pprof at host:AdminPort/debug/pprof.
+	EnableProfiling bool `json:"enableProfiling,omitempty"`
+	// EnableContentionProfiling enables lock contention profiling. Requires
+	// EnableProfiling.
+	EnableContentionProfiling bool `json:"enableContentionProfiling,omitempty"`
+
+	// ServerID is the unique ID of this server.
+	ServerID string `json:"serverID,omitempty"`
+	// ServerCount is the number of proxy server instances, should be 1
+	// unless it is an HA server.
+	ServerCount int `json:"serverCount,omitempty"`
+
+	// ProxyStrategies is the list of proxy strategies used by the server to
+	// pick an agent/tunnel, e.g. "destHost,destCIDR,default".
+	ProxyStrategies string `json:"proxyStrategies,omitempty"`
+	// CipherSuites is the list of allowed cipher suites. Has no effect on
+	// TLS1.3.
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+	// XfrChannelSize is the size of the two KNP server channels used for
+	// transferring data.
+	XfrChannelSize int `json:"xfrChannelSize,omitempty"`
+}
+
+// ServerTransportConfiguration secures communication with the "client", i.e.
+// the Kube API Server.
+type ServerTransportConfiguration struct {
+	Cert   string `json:"cert,omitempty"`
+	Key    string `json:"key,omitempty"`
+	CACert string `json:"caCert,omitempty"`
+}
+
+// ClusterTransportConfiguration secures communication with the "agent", i.e.
+// the managed cluster.
+type ClusterTransportConfiguration struct {
+	Cert   string `json:"cert,omitempty"`
+	Key    string `json:"key,omitempty"`
+	CACert string `json:"caCert,omitempty"`
+}
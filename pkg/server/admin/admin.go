@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admin builds the HTTP muxes served on the proxy server's admin
+// listener (AdminBindAddress:AdminPort) and, optionally, its separate
+// metrics listener (MetricsBindAddress:MetricsPort). It hosts pprof and
+// /flagz, when enabled, /configz for diagnosing config drift without
+// shelling into a pod, and Prometheus /metrics.
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/configz"
+)
+
+// Config describes which admin endpoints to install.
+type Config struct {
+	// EnableProfiling installs the pprof handlers at /debug/pprof.
+	EnableProfiling bool
+	// EnableContentionProfiling additionally enables lock contention
+	// profiling at /debug/pprof/block. Only takes effect if EnableProfiling
+	// is also set.
+	EnableContentionProfiling bool
+}
+
+// NewMux builds the admin HTTP mux according to cfg, registers
+// componentName's live configuration (already redacted by the caller) with
+// the configz registry so it is served at /configz, and serves the parsed
+// flags (as reported by flags.Visit) at /flagz. If metricsRegistry is
+// non-nil, /metrics is also registered on this mux; pass nil when metrics
+// are instead served on their own listener via NewMetricsMux.
+func NewMux(cfg Config, componentName string, componentConfig interface{}, flags *pflag.FlagSet, metricsRegistry *prometheus.Registry) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	if cfg.EnableProfiling {
+		mux.HandleFunc("/debug/pprof", pprof.Index)
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		if cfg.EnableContentionProfiling {
+			debug.SetBlockProfileRate(1)
+		}
+	}
+
+	configz.Register(componentName, componentConfig)
+	configz.InstallHandler(mux)
+
+	mux.HandleFunc("/flagz", flagzHandler(flags))
+
+	if metricsRegistry != nil {
+		mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+	}
+
+	return mux
+}
+
+// NewMetricsMux builds the HTTP mux serving Prometheus metrics gathered
+// from registry. Split out from NewMux so metrics can be bound to their own
+// listener (--metrics-bind-address/--metrics-port) when the admin listener
+// is restricted to localhost but Prometheus scrapes from another network.
+func NewMetricsMux(registry *prometheus.Registry) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// flagzHandler returns a handler serving the current value of every flag in
+// flags as JSON, keyed by flag name.
+func flagzHandler(flags *pflag.FlagSet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		values := map[string]string{}
+		flags.VisitAll(func(f *pflag.Flag) {
+			values[f.Name] = f.Value.String()
+		})
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
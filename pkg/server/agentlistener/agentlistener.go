@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package agentlistener accepts agent tunnel connections and tracks their
+// lifetime in pkg/server/metrics.ServerMetrics.Tunnels. The actual gRPC
+// tunnel protocol is handled by Handle; this package only owns the
+// accept loop and the Tunnels gauge so that every code path that ends a
+// connection (protocol error, EOF, agent disconnect) reliably decrements
+// it via defer.
+package agentlistener
+
+import (
+	"net"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+// Serve accepts connections from ln until it returns an error (e.g. because
+// it was closed), handing each to handle and tracking it in m.Tunnels for
+// as long as handle is running.
+func Serve(ln net.Listener, m *metrics.ServerMetrics, handle func(net.Conn)) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			m.Tunnels.Inc()
+			defer m.Tunnels.Dec()
+			defer conn.Close()
+			klog.V(2).Infof("accepted agent tunnel from %s", conn.RemoteAddr())
+			handle(conn)
+		}()
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agentlistener
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+func TestServeTracksTunnelGauge(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	m := metrics.NewServerMetrics()
+	handling := make(chan struct{})
+	release := make(chan struct{})
+	go Serve(ln, m, func(net.Conn) {
+		close(handling)
+		<-release
+	})
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case <-handling:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for connection to be handled")
+	}
+
+	if got := testutil.ToFloat64(m.Tunnels); got != 1 {
+		t.Errorf("Tunnels = %v, want 1 while connection is active", got)
+	}
+
+	close(release)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if testutil.ToFloat64(m.Tunnels) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Tunnels = %v, want 0 after connection closes", testutil.ToFloat64(m.Tunnels))
+}
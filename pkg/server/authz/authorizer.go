@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AgentIdentity is what the backend-selection path (pkg/server/proxystrategies)
+// knows about an authenticated agent at authorization time.
+type AgentIdentity struct {
+	// ServiceAccount is the "system:serviceaccount:<ns>:<name>" identity
+	// established by SA-token-based agent authentication, or empty if the
+	// agent authenticated via mTLS CA only.
+	ServiceAccount string
+	// NodeAddr is the agent's source address.
+	NodeAddr net.IP
+	// Labels are the agent's advertised identifiers.
+	Labels map[string]string
+}
+
+// Authorizer decides whether an authenticated agent may be selected to dial
+// a destination. It is consulted in the backend-selection path used by the
+// "destHost", "destCIDR", and "default" proxy strategies, after an agent
+// has already passed mTLS/SA-token authentication.
+type Authorizer interface {
+	// AuthorizeDial returns nil if agent is permitted to dial destHost, or a
+	// gRPC status error (PermissionDenied) otherwise.
+	AuthorizeDial(agent AgentIdentity, destHost string) error
+}
+
+// alwaysAllow is the Authorizer used when no --agent-authorization-config
+// is set, preserving today's behavior of trusting any authenticated agent.
+type alwaysAllow struct{}
+
+func (alwaysAllow) AuthorizeDial(AgentIdentity, string) error { return nil }
+
+// AlwaysAllowAuthorizer is the default Authorizer: it permits any
+// authenticated agent to dial any destination.
+var AlwaysAllowAuthorizer Authorizer = alwaysAllow{}
+
+// ruleAuthorizer authorizes dials against a fixed set of Rules.
+type ruleAuthorizer struct {
+	cfg *Config
+}
+
+// NewAuthorizer builds an Authorizer that enforces cfg. cfg should already
+// have been validated with Validate.
+func NewAuthorizer(cfg *Config) Authorizer {
+	return &ruleAuthorizer{cfg: cfg}
+}
+
+func (a *ruleAuthorizer) AuthorizeDial(agent AgentIdentity, destHost string) error {
+	for _, rule := range a.cfg.Rules {
+		if !matches(rule.Match, agent) {
+			continue
+		}
+		if allows(rule.Allow, destHost) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "agent %+v is not authorized to dial %q", agent, destHost)
+}
+
+func matches(m Match, agent AgentIdentity) bool {
+	if m.ServiceAccount != "" && m.ServiceAccount != agent.ServiceAccount {
+		return false
+	}
+	if m.NodeCIDR != "" {
+		_, cidr, err := net.ParseCIDR(m.NodeCIDR)
+		if err != nil || agent.NodeAddr == nil || !cidr.Contains(agent.NodeAddr) {
+			return false
+		}
+	}
+	for k, v := range m.Labels {
+		if agent.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func allows(allow Allow, destHost string) bool {
+	if ip := net.ParseIP(destHost); ip != nil {
+		for _, cidr := range allow.DestCIDRs {
+			if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	for _, suffix := range allow.DestHostSuffixes {
+		if strings.HasSuffix(destHost, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"net"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		match Match
+		agent AgentIdentity
+		want  bool
+	}{
+		{
+			name:  "empty match allows any agent",
+			match: Match{},
+			agent: AgentIdentity{},
+			want:  true,
+		},
+		{
+			name:  "service account mismatch",
+			match: Match{ServiceAccount: "system:serviceaccount:ns:a"},
+			agent: AgentIdentity{ServiceAccount: "system:serviceaccount:ns:b"},
+			want:  false,
+		},
+		{
+			name:  "service account match",
+			match: Match{ServiceAccount: "system:serviceaccount:ns:a"},
+			agent: AgentIdentity{ServiceAccount: "system:serviceaccount:ns:a"},
+			want:  true,
+		},
+		{
+			name:  "node CIDR contains address",
+			match: Match{NodeCIDR: "10.0.0.0/8"},
+			agent: AgentIdentity{NodeAddr: net.ParseIP("10.1.2.3")},
+			want:  true,
+		},
+		{
+			name:  "node CIDR excludes address",
+			match: Match{NodeCIDR: "10.0.0.0/8"},
+			agent: AgentIdentity{NodeAddr: net.ParseIP("192.168.1.1")},
+			want:  false,
+		},
+		{
+			name:  "node CIDR with no agent address",
+			match: Match{NodeCIDR: "10.0.0.0/8"},
+			agent: AgentIdentity{},
+			want:  false,
+		},
+		{
+			name:  "labels all match",
+			match: Match{Labels: map[string]string{"region": "us-east"}},
+			agent: AgentIdentity{Labels: map[string]string{"region": "us-east", "zone": "a"}},
+			want:  true,
+		},
+		{
+			name:  "labels missing",
+			match: Match{Labels: map[string]string{"region": "us-east"}},
+			agent: AgentIdentity{Labels: map[string]string{"zone": "a"}},
+			want:  false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matches(tc.match, tc.agent); got != tc.want {
+				t.Errorf("matches() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    Allow
+		destHost string
+		want     bool
+	}{
+		{
+			name:     "destCIDR contains IP",
+			allow:    Allow{DestCIDRs: []string{"10.0.0.0/8"}},
+			destHost: "10.1.2.3",
+			want:     true,
+		},
+		{
+			name:     "destCIDR excludes IP",
+			allow:    Allow{DestCIDRs: []string{"10.0.0.0/8"}},
+			destHost: "192.168.1.1",
+			want:     false,
+		},
+		{
+			name:     "hostname suffix match",
+			allow:    Allow{DestHostSuffixes: []string{".svc.cluster.local"}},
+			destHost: "kubernetes.default.svc.cluster.local",
+			want:     true,
+		},
+		{
+			name:     "hostname suffix mismatch",
+			allow:    Allow{DestHostSuffixes: []string{".svc.cluster.local"}},
+			destHost: "example.com",
+			want:     false,
+		},
+		{
+			name:     "empty allow permits nothing",
+			allow:    Allow{},
+			destHost: "10.1.2.3",
+			want:     false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := allows(tc.allow, tc.destHost); got != tc.want {
+				t.Errorf("allows() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeDial(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{
+				Match: Match{NodeCIDR: "10.0.0.0/8"},
+				Allow: Allow{DestHostSuffixes: []string{".svc.cluster.local"}},
+			},
+		},
+	}
+	authorizer := NewAuthorizer(cfg)
+
+	allowedAgent := AgentIdentity{NodeAddr: net.ParseIP("10.1.2.3")}
+	if err := authorizer.AuthorizeDial(allowedAgent, "kubernetes.default.svc.cluster.local"); err != nil {
+		t.Errorf("AuthorizeDial() = %v, want nil", err)
+	}
+
+	if err := authorizer.AuthorizeDial(allowedAgent, "example.com"); err == nil {
+		t.Error("AuthorizeDial() = nil, want error for disallowed destination")
+	}
+
+	otherAgent := AgentIdentity{NodeAddr: net.ParseIP("192.168.1.1")}
+	if err := authorizer.AuthorizeDial(otherAgent, "kubernetes.default.svc.cluster.local"); err == nil {
+		t.Error("AuthorizeDial() = nil, want error for unmatched agent")
+	}
+}
+
+func TestAlwaysAllowAuthorizer(t *testing.T) {
+	if err := AlwaysAllowAuthorizer.AuthorizeDial(AgentIdentity{}, "anything"); err != nil {
+		t.Errorf("AlwaysAllowAuthorizer.AuthorizeDial() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LoadConfig reads and parses the agent authorization config at path. It
+// does not validate the CIDRs within; callers should also call Validate.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --agent-authorization-config %q: %v", path, err)
+	}
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse --agent-authorization-config %q: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate rejects a Config containing malformed CIDRs.
+func Validate(cfg *Config) error {
+	for i, rule := range cfg.Rules {
+		if rule.Match.NodeCIDR != "" {
+			if _, _, err := net.ParseCIDR(rule.Match.NodeCIDR); err != nil {
+				return fmt.Errorf("rules[%d].match.nodeCIDR %q is invalid: %v", i, rule.Match.NodeCIDR, err)
+			}
+		}
+		for _, cidr := range rule.Allow.DestCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("rules[%d].allow.destCIDRs contains invalid CIDR %q: %v", i, cidr, err)
+			}
+		}
+	}
+	return nil
+}
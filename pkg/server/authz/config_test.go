@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{
+			name: "valid CIDRs",
+			cfg: &Config{Rules: []Rule{{
+				Match: Match{NodeCIDR: "10.0.0.0/8"},
+				Allow: Allow{DestCIDRs: []string{"192.168.0.0/16"}},
+			}}},
+			wantErr: false,
+		},
+		{
+			name: "invalid node CIDR",
+			cfg: &Config{Rules: []Rule{{
+				Match: Match{NodeCIDR: "not-a-cidr"},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "invalid dest CIDR",
+			cfg: &Config{Rules: []Rule{{
+				Allow: Allow{DestCIDRs: []string{"not-a-cidr"}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:    "no rules",
+			cfg:     &Config{},
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authz implements post-authentication authorization for which
+// dial targets an authenticated agent may serve, configured via
+// --agent-authorization-config.
+package authz
+
+// Config is the on-disk, YAML representation of --agent-authorization-config.
+// An agent is authorized for a dial target if at least one rule's Match
+// selects it and its Allow permits the target.
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule ties a set of agents (Match) to the dial targets they may serve
+// (Allow).
+type Rule struct {
+	Match Match `json:"match"`
+	Allow Allow `json:"allow"`
+}
+
+// Match selects which agents a rule applies to. All non-empty fields must
+// match for the rule to apply; an empty field is ignored.
+type Match struct {
+	// ServiceAccount is the expected "system:serviceaccount:<ns>:<name>"
+	// identity of the agent, as established by SA-token-based agent
+	// authentication.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// NodeCIDR restricts the rule to agents connecting from an address
+	// within this CIDR.
+	NodeCIDR string `json:"nodeCIDR,omitempty"`
+	// Labels restricts the rule to agents advertising all of these
+	// key/value pairs (via the agent identifiers the agent registers with).
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Allow describes which dial targets a matched agent may serve.
+type Allow struct {
+	// DestCIDRs is the list of CIDRs the agent may dial into.
+	DestCIDRs []string `json:"destCIDRs,omitempty"`
+	// DestHostSuffixes is the list of hostname suffixes the agent may dial,
+	// e.g. ".svc.cluster.local".
+	DestHostSuffixes []string `json:"destHostSuffixes,omitempty"`
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certreload lets the frontend and agent listeners pick up renewed
+// certificates, cipher suites, and proxy strategies without dropping
+// existing tunnels, driven by --enable-config-reload.
+package certreload
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+)
+
+// CertReloader holds the current server certificate behind an atomic
+// pointer, so in-flight handshakes always see a consistent *tls.Certificate
+// even while Reload swaps in a newly rotated one.
+type CertReloader struct {
+	certPath, keyPath string
+	current           atomic.Pointer[tls.Certificate]
+}
+
+// NewCertReloader loads the cert/key pair at certPath/keyPath and returns a
+// CertReloader serving it.
+func NewCertReloader(certPath, keyPath string) (*CertReloader, error) {
+	r := &CertReloader{certPath: certPath, keyPath: keyPath}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the cert/key pair from disk and atomically swaps it in.
+// Callers already mid-handshake continue to see whichever cert was current
+// when GetCertificate/GetClientCertificate was called.
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to reload cert/key pair (%s, %s): %v", r.certPath, r.keyPath, err)
+	}
+	r.current.Store(&cert)
+	return nil
+}
+
+// GetCertificate is a tls.Config.GetCertificate callback serving the
+// current certificate, for use on the server-facing (frontend/agent)
+// listeners.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current.Load(), nil
+}
+
+// GetClientCertificate is a tls.Config.GetClientCertificate callback
+// serving the current certificate, for use where this process is itself a
+// TLS client presenting a certificate (e.g. mTLS to the agent).
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.current.Load(), nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"path/filepath"
+	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+func writeSelfSignedCert(t *testing.T, dir, name string) (certPath, keyPath string) {
+	t.Helper()
+	cert, key, err := certutil.GenerateSelfSignedCertKeyWithFixtures("localhost", nil, nil, "")
+	if err != nil {
+		t.Fatalf("failed to generate self-signed cert: %v", err)
+	}
+	certPath = filepath.Join(dir, name+".crt")
+	keyPath = filepath.Join(dir, name+".key")
+	if err := certutil.WriteCert(certPath, cert); err != nil {
+		t.Fatalf("failed to write cert: %v", err)
+	}
+	if err := keyutil.WriteKey(keyPath, key); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return certPath, keyPath
+}
+
+func TestCertReloaderReload(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeSelfSignedCert(t, dir, "original")
+
+	reloader, err := NewCertReloader(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("NewCertReloader() = %v", err)
+	}
+	original, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate() = %v", err)
+	}
+
+	rotatedCert, rotatedKey, err := certutil.GenerateSelfSignedCertKeyWithFixtures("localhost", nil, nil, "")
+	if err != nil {
+		t.Fatalf("failed to generate rotated cert: %v", err)
+	}
+	if err := certutil.WriteCert(certPath, rotatedCert); err != nil {
+		t.Fatalf("failed to install rotated cert: %v", err)
+	}
+	if err := keyutil.WriteKey(keyPath, rotatedKey); err != nil {
+		t.Fatalf("failed to install rotated key: %v", err)
+	}
+
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	reloaded, err := reloader.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetClientCertificate() = %v", err)
+	}
+
+	if string(reloaded.Certificate[0]) == string(original.Certificate[0]) {
+		t.Error("GetCertificate() still returns the pre-rotation certificate after Reload()")
+	}
+}
+
+func TestCertReloaderRejectsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewCertReloader(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key")); err == nil {
+		t.Error("NewCertReloader() = nil error, want error for missing cert/key files")
+	}
+}
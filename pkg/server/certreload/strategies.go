@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/proxystrategies"
+)
+
+// strategyConfig is the parsed, immutable result of a single
+// ProxyStrategies/CipherSuites reload.
+type strategyConfig struct {
+	strategies []proxystrategies.ProxyStrategy
+	ciphers    []string
+	cipherIDs  []uint16
+}
+
+// StrategyReloader holds the server's ProxyStrategies and CipherSuites
+// behind an atomic pointer so they can be re-parsed and swapped in
+// together, without the backend-selection path or TLS config ever
+// observing a half-updated value.
+type StrategyReloader struct {
+	current atomic.Pointer[strategyConfig]
+}
+
+// NewStrategyReloader parses and stores the initial proxyStrategies/
+// cipherSuites values.
+func NewStrategyReloader(proxyStrategies string, cipherSuites []string) (*StrategyReloader, error) {
+	r := &StrategyReloader{}
+	if err := r.Reload(proxyStrategies, cipherSuites); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-parses proxyStrategies/cipherSuites and, if both are valid,
+// atomically swaps them in, resolving cipherSuites to their IDs once so
+// CipherSuiteIDs doesn't redo that work on every call.
+func (r *StrategyReloader) Reload(proxyStrategies string, cipherSuites []string) error {
+	parsedStrategies, err := proxystrategies.ParseProxyStrategies(proxyStrategies)
+	if err != nil {
+		return fmt.Errorf("invalid proxy strategies: %v", err)
+	}
+	var cipherIDs []uint16
+	if len(cipherSuites) != 0 {
+		cipherIDs, err = resolveCipherSuiteIDs(cipherSuites)
+		if err != nil {
+			return err
+		}
+	}
+	r.current.Store(&strategyConfig{strategies: parsedStrategies, ciphers: cipherSuites, cipherIDs: cipherIDs})
+	return nil
+}
+
+// ProxyStrategies returns the currently active, parsed proxy strategies.
+func (r *StrategyReloader) ProxyStrategies() []proxystrategies.ProxyStrategy {
+	return r.current.Load().strategies
+}
+
+// CipherSuites returns the currently active cipher suite names.
+func (r *StrategyReloader) CipherSuites() []string {
+	return r.current.Load().ciphers
+}
+
+// CipherSuiteIDs returns the IDs CipherSuites were resolved to at the last
+// Reload, for use as a tls.Config's CipherSuites field. It's nil (the
+// stdlib default list) if no cipher suites are configured. Cheap enough to
+// call fresh from a tls.Config.GetConfigForClient hook on every handshake,
+// so that a config reload is reflected on the very next one.
+func (r *StrategyReloader) CipherSuiteIDs() []uint16 {
+	return r.current.Load().cipherIDs
+}
+
+// resolveCipherSuiteIDs maps cipher suite names to IDs against the standard
+// library's named suites, rejecting any name neither lists.
+func resolveCipherSuiteIDs(names []string) ([]uint16, error) {
+	byName := make(map[string]uint16)
+	for _, c := range tls.CipherSuites() {
+		byName[c.Name] = c.ID
+	}
+	for _, c := range tls.InsecureCipherSuites() {
+		byName[c.Name] = c.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("cipher suite %s not supported, doesn't exist or considered as insecure", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
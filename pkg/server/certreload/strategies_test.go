@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import "testing"
+
+func TestStrategyReloaderReload(t *testing.T) {
+	r, err := NewStrategyReloader("default", nil)
+	if err != nil {
+		t.Fatalf("NewStrategyReloader() = %v", err)
+	}
+	if len(r.ProxyStrategies()) == 0 {
+		t.Error("ProxyStrategies() is empty after constructing with \"default\"")
+	}
+
+	if err := r.Reload("default", []string{"not-a-real-cipher"}); err == nil {
+		t.Error("Reload() = nil error, want error for an unsupported cipher suite")
+	}
+	// A failed Reload must not clobber the previously valid configuration.
+	if len(r.ProxyStrategies()) == 0 {
+		t.Error("ProxyStrategies() is empty after a failed Reload()")
+	}
+
+	if err := r.Reload("not-a-real-strategy", nil); err == nil {
+		t.Error("Reload() = nil error, want error for an unknown proxy strategy")
+	}
+}
+
+func TestStrategyReloaderCipherSuiteIDs(t *testing.T) {
+	r, err := NewStrategyReloader("default", nil)
+	if err != nil {
+		t.Fatalf("NewStrategyReloader() = %v", err)
+	}
+	if ids := r.CipherSuiteIDs(); ids != nil {
+		t.Errorf("CipherSuiteIDs() = %v, want nil with no cipher suites configured", ids)
+	}
+
+	if err := r.Reload("default", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	ids := r.CipherSuiteIDs()
+	if len(ids) != 1 || ids[0] == 0 {
+		t.Errorf("CipherSuiteIDs() = %v, want a single non-zero resolved ID", ids)
+	}
+
+	if err := r.Reload("default", []string{"not-a-real-cipher"}); err == nil {
+		t.Error("Reload() = nil error, want error for an unsupported cipher suite")
+	}
+	// A failed Reload must not clobber the previously resolved IDs.
+	if len(r.CipherSuiteIDs()) != 1 {
+		t.Errorf("CipherSuiteIDs() = %v, want unchanged after a failed Reload()", r.CipherSuiteIDs())
+	}
+}
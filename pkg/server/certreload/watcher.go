@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+)
+
+// Watcher invokes onReload whenever the process receives SIGHUP or any of
+// the watched files change on disk (e.g. a cert-manager renewal writing a
+// new cert/key pair).
+type Watcher struct {
+	onReload func() error
+	fsw      *fsnotify.Watcher
+}
+
+// NewWatcher builds a Watcher that calls onReload on SIGHUP or whenever one
+// of paths changes. Empty paths are ignored, so callers can pass e.g.
+// ServerCert/ServerKey/ClusterCert/ClusterKey/ConfigFile unconditionally.
+func NewWatcher(onReload func() error, paths ...string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	watchedDirs := map[string]bool{}
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		dir := filepath.Dir(p)
+		if watchedDirs[dir] {
+			continue
+		}
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+		watchedDirs[dir] = true
+	}
+	return &Watcher{onReload: onReload, fsw: fsw}, nil
+}
+
+// Run blocks, triggering onReload on SIGHUP or file-change events, until
+// stopCh is closed.
+func (w *Watcher) Run(stopCh <-chan struct{}) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+	defer w.fsw.Close()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-sighup:
+			w.trigger("SIGHUP")
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				w.trigger(event.Name)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			klog.Errorf("config reload watcher error: %v", err)
+		}
+	}
+}
+
+func (w *Watcher) trigger(source string) {
+	klog.Infof("reloading server configuration (triggered by %s)", source)
+	if err := w.onReload(); err != nil {
+		klog.Errorf("failed to reload server configuration: %v", err)
+	}
+}
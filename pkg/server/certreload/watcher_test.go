@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherTriggersOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	watched := filepath.Join(dir, "server.crt")
+	if err := os.WriteFile(watched, []byte("v1"), 0644); err != nil {
+		t.Fatalf("failed to write initial file: %v", err)
+	}
+
+	reloaded := make(chan struct{}, 1)
+	w, err := NewWatcher(func() error {
+		reloaded <- struct{}{}
+		return nil
+	}, watched)
+	if err != nil {
+		t.Fatalf("NewWatcher() = %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go w.Run(stopCh)
+
+	if err := os.WriteFile(watched, []byte("v2"), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for onReload to be called after file change")
+	}
+}
+
+func TestWatcherIgnoresEmptyPaths(t *testing.T) {
+	w, err := NewWatcher(func() error { return nil }, "", "")
+	if err != nil {
+		t.Fatalf("NewWatcher() = %v", err)
+	}
+	stopCh := make(chan struct{})
+	close(stopCh)
+	w.Run(stopCh)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configz is a small in-repo registry of running component
+// configuration, analogous to k8s.io/component-base/configz. Components
+// register their live configuration under a name (e.g. "proxy-server"), and
+// InstallHandler serves all registered configs as JSON, keyed by component
+// name, at the mux path it is installed under (conventionally /configz).
+package configz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var (
+	mutex   sync.RWMutex
+	configs = map[string]interface{}{}
+)
+
+// Config is a handle to a registered configuration. Calling Delete removes
+// it from the registry.
+type Config struct {
+	name string
+}
+
+// Register records value under name so it is served at /configz. It
+// replaces any existing registration under the same name.
+func Register(name string, value interface{}) *Config {
+	mutex.Lock()
+	defer mutex.Unlock()
+	configs[name] = value
+	return &Config{name: name}
+}
+
+// Delete removes c's configuration from the registry.
+func (c *Config) Delete() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(configs, c.name)
+}
+
+// InstallHandler registers the /configz handler on mux.
+func InstallHandler(mux *http.ServeMux) {
+	mux.HandleFunc("/configz", handle)
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	body := map[string]interface{}{"componentconfig": configs}
+	data, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error marshaling configz: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configz
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleServesRegisteredConfig(t *testing.T) {
+	c := Register("proxy-server", map[string]string{"mode": "grpc"})
+	defer c.Delete()
+
+	req := httptest.NewRequest(http.MethodGet, "/configz", nil)
+	w := httptest.NewRecorder()
+	handle(w, req)
+
+	var body struct {
+		ComponentConfig map[string]interface{} `json:"componentconfig"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if _, ok := body.ComponentConfig["proxy-server"]; !ok {
+		t.Errorf("expected \"proxy-server\" in componentconfig, got %v", body.ComponentConfig)
+	}
+}
+
+func TestDeleteRemovesConfig(t *testing.T) {
+	c := Register("proxy-agent", "x")
+	c.Delete()
+
+	req := httptest.NewRequest(http.MethodGet, "/configz", nil)
+	w := httptest.NewRecorder()
+	handle(w, req)
+
+	var body struct {
+		ComponentConfig map[string]interface{} `json:"componentconfig"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if _, ok := body.ComponentConfig["proxy-agent"]; ok {
+		t.Errorf("expected \"proxy-agent\" to be removed, got %v", body.ComponentConfig)
+	}
+}
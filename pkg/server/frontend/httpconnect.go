@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package frontend implements the "http-connect" mode frontend listener:
+// an http.Handler that authorizes and dials HTTP CONNECT requests from the
+// Kube API Server, then splices the resulting connection. This is where
+// dial-time Prometheus metrics (pkg/server/metrics) are observed for
+// http-connect mode; grpc mode is metered at the equivalent point in the
+// gRPC tunnel's backend-selection code. The Authorizer hook exists for
+// shape-compatibility with that backend-selection path, but there is no
+// agent identity to authorize here — see agentIdentityFromRequest — so
+// real --agent-authorization-config enforcement belongs only to grpc mode.
+package frontend
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/authz"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+const proxyStrategyLabel = "http-connect"
+
+// Dialer dials a connection to destHost on behalf of a CONNECT request,
+// typically by selecting and tunneling through an agent.
+type Dialer interface {
+	DialContext(ctx context.Context, destHost string) (net.Conn, error)
+}
+
+// Handler is an http.Handler serving HTTP CONNECT requests.
+type Handler struct {
+	Authorizer authz.Authorizer
+	Dialer     Dialer
+	Metrics    *metrics.ServerMetrics
+}
+
+// NewHandler builds a Handler. If authorizer is nil, authz.AlwaysAllowAuthorizer
+// is used, preserving today's behavior of trusting any authenticated agent.
+func NewHandler(authorizer authz.Authorizer, dialer Dialer, m *metrics.ServerMetrics) *Handler {
+	if authorizer == nil {
+		authorizer = authz.AlwaysAllowAuthorizer
+	}
+	return &Handler{Authorizer: authorizer, Dialer: dialer, Metrics: m}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "this listener only serves CONNECT", http.StatusMethodNotAllowed)
+		return
+	}
+	destHost := r.Host
+
+	agent := agentIdentityFromRequest(r)
+	if err := h.Authorizer.AuthorizeDial(agent, destHost); err != nil {
+		h.Metrics.BackendSelections.WithLabelValues(proxyStrategyLabel, "denied").Inc()
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	start := time.Now()
+	backendConn, err := h.Dialer.DialContext(r.Context(), destHost)
+	h.Metrics.DialLatencies.WithLabelValues(proxyStrategyLabel).Observe(time.Since(start).Seconds())
+	if err != nil {
+		h.Metrics.BackendSelections.WithLabelValues(proxyStrategyLabel, "error").Inc()
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	h.Metrics.BackendSelections.WithLabelValues(proxyStrategyLabel, "allowed").Inc()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		backendConn.Close()
+		http.Error(w, "connection hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	clientConn, _, err := hijacker.Hijack()
+	if err != nil {
+		backendConn.Close()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	clientConn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+	h.Metrics.Connections.Inc()
+	defer h.Metrics.Connections.Dec()
+	proxyData(clientConn, backendConn)
+}
+
+// proxyData splices a and b until either side closes.
+func proxyData(a, b net.Conn) {
+	defer a.Close()
+	defer b.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// agentIdentityFromRequest extracts what we know about the dialing HTTP
+// client for authorization purposes: its NodeAddr. That client is the Kube
+// API Server, not an agent — there is no agent in this request's path at
+// all, so ServiceAccount and Labels are never populated, and an
+// --agent-authorization-config rule written against an agent's identity
+// (e.g. matching its ServiceAccount) can never match here. Callers must not
+// point --agent-authorization-config at a Handler built around this
+// function; cmd/server/app/frontend.go enforces that by always using
+// authz.AlwaysAllowAuthorizer for this listener and rejecting the flag
+// combination in Validate.
+func agentIdentityFromRequest(r *http.Request) authz.AgentIdentity {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		klog.V(4).Infof("could not parse remote addr %q: %v", r.RemoteAddr, err)
+		return authz.AgentIdentity{}
+	}
+	return authz.AgentIdentity{NodeAddr: net.ParseIP(host)}
+}
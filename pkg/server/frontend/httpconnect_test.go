@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/authz"
+	"sigs.k8s.io/apiserver-network-proxy/pkg/server/metrics"
+)
+
+type fakeDialer struct {
+	called bool
+}
+
+func (f *fakeDialer) DialContext(ctx context.Context, destHost string) (net.Conn, error) {
+	f.called = true
+	return nil, fmt.Errorf("fakeDialer does not actually dial")
+}
+
+type denyAuthorizer struct{}
+
+func (denyAuthorizer) AuthorizeDial(authz.AgentIdentity, string) error {
+	return status.Errorf(codes.PermissionDenied, "denied by test")
+}
+
+func TestServeHTTPRejectsNonConnect(t *testing.T) {
+	h := NewHandler(nil, &fakeDialer{}, metrics.NewServerMetrics())
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	if w.Code != 405 {
+		t.Errorf("status = %d, want 405", w.Code)
+	}
+}
+
+func TestServeHTTPDeniesUnauthorizedDial(t *testing.T) {
+	dialer := &fakeDialer{}
+	h := NewHandler(denyAuthorizer{}, dialer, metrics.NewServerMetrics())
+	req := httptest.NewRequest("CONNECT", "http://10.244.1.2:443", nil)
+	req.Host = "10.244.1.2:443"
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	if w.Code != 403 {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+	if dialer.called {
+		t.Errorf("expected denied dial to never reach the Dialer")
+	}
+}